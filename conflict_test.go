@@ -0,0 +1,67 @@
+package copy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCopyOverwriteReplacesConflictingType(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src")
+	dest := filepath.Join(dir, "dest")
+
+	if err := os.Mkdir(src, 0755); err != nil {
+		t.Fatalf("Mkdir(src): %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "a"), []byte("a"), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	// dest already exists, but as a plain file: a type conflict with src (a directory).
+	if err := os.WriteFile(dest, []byte("stale"), 0644); err != nil {
+		t.Fatalf("WriteFile(dest): %v", err)
+	}
+
+	if err := Copy(src, dest, Options{Overwrite: true}); err != nil {
+		t.Fatalf("Copy: %v", err)
+	}
+
+	info, err := os.Stat(dest)
+	if err != nil {
+		t.Fatalf("Stat(dest): %v", err)
+	}
+	if !info.IsDir() {
+		t.Fatalf("dest is not a directory after Overwrite copy")
+	}
+}
+
+func TestCopyPruneDeletesStaleDestEntries(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src")
+	dest := filepath.Join(dir, "dest")
+
+	if err := os.Mkdir(src, 0755); err != nil {
+		t.Fatalf("Mkdir(src): %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "keep"), []byte("keep"), 0644); err != nil {
+		t.Fatalf("WriteFile(keep): %v", err)
+	}
+
+	if err := os.Mkdir(dest, 0755); err != nil {
+		t.Fatalf("Mkdir(dest): %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dest, "stale"), []byte("stale"), 0644); err != nil {
+		t.Fatalf("WriteFile(stale): %v", err)
+	}
+
+	if err := Copy(src, dest, Options{Prune: true}); err != nil {
+		t.Fatalf("Copy: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dest, "stale")); !os.IsNotExist(err) {
+		t.Errorf("stale entry still present: err = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dest, "keep")); err != nil {
+		t.Errorf("keep entry missing: %v", err)
+	}
+}
@@ -1,13 +1,16 @@
 package copy
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"syscall"
-	"time"
 )
 
 const (
@@ -31,17 +34,30 @@ var (
 type FileCopyHandler func(src, dest string, info os.FileInfo) error
 
 var FileTypeCopyHandlers = map[os.FileMode]FileCopyHandler{
-	os.ModeSymlink: lcopy,
+	os.ModeNamedPipe:                  pcopy,
+	os.ModeDevice | os.ModeCharDevice: nodecopy,
 }
 
-// Copy copies src to dest, doesn't matter if src is a directory or a file
-func Copy(src, dest string) error {
+// Copy copies src to dest, doesn't matter if src is a directory or a file.
+// An Options value may be passed to customize the copy; if omitted, the
+// legacy package-level globals (PreservePermissions, PreserveOwner,
+// PreserveTime) are used instead, so existing callers keep working unchanged.
+func Copy(src, dest string, opts ...Options) error {
+	return CopyWithContext(context.Background(), src, dest, opts...)
+}
+
+// CopyWithContext is Copy, but aborts as soon as ctx is done, returning
+// ctx.Err(). Cancellation is checked before recursing into each file or
+// directory, and between chunks of a large file's content copy.
+func CopyWithContext(ctx context.Context, src, dest string, opts ...Options) error {
+	o := optionsOrDefault(opts)
+
 	info, err := os.Lstat(src)
 	if err != nil {
 		return err
 	}
 
-	err = copy(src, dest, info)
+	err = copy(ctx, src, dest, info, o)
 	if err != nil {
 		// If we encountered an unsupported file type, exit only if we don't ignore them
 		if _, ok := err.(*UnsupportedFileTypeError); ok {
@@ -59,33 +75,46 @@ func Copy(src, dest string) error {
 // copy dispatches copy-funcs according to the mode.
 // Because this "copy" could be called recursively,
 // "info" MUST be given here, NOT nil.
-func copy(src, dest string, info os.FileInfo) error {
+func copy(ctx context.Context, src, dest string, info os.FileInfo, opts Options) error {
 
-	var err error
-	if info.Mode().IsRegular() {
-		err = fcopy(src, dest, info)
-	} else if info.IsDir() {
-		err = dcopy(src, dest, info)
-	} else {
-		for fileType, handler := range FileTypeCopyHandlers {
-			if info.Mode()&fileType != 0 {
-				err = handler(src, dest, info)
-				break
-			}
-		}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 
-		err = &UnsupportedFileTypeError{
-			mode: info.Mode(),
-			path: src,
+	if opts.Skip != nil {
+		skip, err := opts.Skip(info, src, dest)
+		if err != nil {
+			return err
+		}
+		if skip {
+			return nil
 		}
 	}
 
+	skip, err := resolveConflict(src, dest, info, opts)
+	if err != nil {
+		return err
+	}
+	if skip {
+		return nil
+	}
+
+	if info.Mode()&os.ModeSymlink != 0 {
+		err = symlinkCopy(ctx, src, dest, info, opts)
+	} else if info.Mode().IsRegular() {
+		err = fcopy(ctx, src, dest, info, opts)
+	} else if info.IsDir() {
+		err = dcopy(ctx, src, dest, info, opts)
+	} else {
+		err = typeCopy(src, dest, info, opts)
+	}
+
 	if err != nil {
 		return err
 	}
 
 	var errs []error
-	if PreservePermissions {
+	if opts.PreservePermissions {
 		err = os.Chmod(dest, info.Mode().Perm())
 		if err != nil {
 			err = fmt.Errorf("could not restore permissions '%s' for file %s: %w", info.Mode().Perm().String(), dest, err)
@@ -93,16 +122,12 @@ func copy(src, dest string, info os.FileInfo) error {
 		}
 	}
 
-	var stat *syscall.Stat_t
-	if PreserveOwner || PreserveTime {
-		stat, _ = info.Sys().(*syscall.Stat_t)
-	}
-
-	if PreserveOwner {
-		if stat != nil {
-			err = os.Lchown(dest, int(stat.Uid), int(stat.Gid))
+	if opts.PreserveOwner {
+		uid, gid, ok := owner(info)
+		if ok {
+			err = os.Lchown(dest, uid, gid)
 			if err != nil {
-				err = fmt.Errorf("could not restore owner %d:%d for file %s: %w", stat.Uid, stat.Gid, dest, err)
+				err = fmt.Errorf("could not restore owner %d:%d for file %s: %w", uid, gid, dest, err)
 			}
 		} else {
 			err = fmt.Errorf("could not restore owner for file %s: %w", dest, err)
@@ -113,20 +138,11 @@ func copy(src, dest string, info os.FileInfo) error {
 		}
 	}
 
-	if PreserveTime {
-		if stat != nil {
-			atime := time.Unix(int64(stat.Atim.Sec), int64(stat.Atim.Nsec))
-			mtime := info.ModTime()
-			err = os.Chtimes(dest, atime, mtime)
-			if err != nil {
-				err = fmt.Errorf("could not restore timestamp '%s' for file %s: %w", info.ModTime().String(), dest, err)
-			}
-
-		} else {
-			err = fmt.Errorf("could not restore timestamp for file %s: %w", dest, err)
-		}
-
+	if opts.PreserveTime {
+		atime, mtime, _ := times(info)
+		err = lutimes(dest, atime, mtime)
 		if err != nil {
+			err = fmt.Errorf("could not restore timestamp '%s' for file %s: %w", mtime.String(), dest, err)
 			errs = append(errs, err)
 		}
 	}
@@ -141,22 +157,137 @@ func copy(src, dest string, info os.FileInfo) error {
 	}
 }
 
+// resolveConflict Lstats dest and, if it already exists with a type
+// different from src (e.g. a file where src is a directory), consults
+// opts.OnConflict (or opts.Overwrite) to decide how to proceed. It returns
+// skip=true when the caller should leave dest untouched.
+func resolveConflict(src, dest string, srcInfo os.FileInfo, opts Options) (skip bool, err error) {
+	destInfo, err := os.Lstat(dest)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	if (srcInfo.Mode() & os.ModeType) == (destInfo.Mode() & os.ModeType) {
+		return false, nil
+	}
+
+	onConflict := opts.OnConflict
+	if onConflict == nil {
+		if !opts.Overwrite {
+			// No policy configured: leave it for the underlying copy to fail
+			// on, same as before OnConflict existed.
+			return false, nil
+		}
+		onConflict = func(string, string, os.FileInfo, os.FileInfo) ConflictAction {
+			return ConflictReplace
+		}
+	}
+
+	switch onConflict(src, dest, srcInfo, destInfo) {
+	case ConflictReplace:
+		return false, os.RemoveAll(dest)
+	case ConflictSkip:
+		return true, nil
+	case ConflictMerge:
+		return false, nil
+	case ConflictFail:
+		return false, &ConflictError{src: src, dest: dest}
+	default:
+		return false, nil
+	}
+}
+
+// symlinkCopy handles a src that is itself a symlink, following opts.OnSymlink
+// to decide whether to replicate the link (Shallow, the default), follow it
+// and copy its target (Deep), or leave dest untouched (Skip).
+func symlinkCopy(ctx context.Context, src, dest string, info os.FileInfo, opts Options) error {
+	action := Shallow
+	if opts.OnSymlink != nil {
+		action = opts.OnSymlink(src)
+	}
+
+	switch action {
+	case Skip:
+		return nil
+	case Deep:
+		orig, err := os.Readlink(src)
+		if err != nil {
+			return err
+		}
+		if !filepath.IsAbs(orig) {
+			orig = filepath.Join(filepath.Dir(src), orig)
+		}
+		origInfo, err := os.Stat(orig)
+		if err != nil {
+			return err
+		}
+		return copy(ctx, orig, dest, origInfo, opts)
+	default:
+		return lcopy(src, dest, info, opts)
+	}
+}
+
+// typeCopy hands off anything that is neither a regular file, a directory,
+// nor a symlink to a registered FileTypeCopyHandler.
+func typeCopy(src, dest string, info os.FileInfo, opts Options) error {
+	for fileType, handler := range FileTypeCopyHandlers {
+		if info.Mode()&fileType != 0 {
+			return handler(src, dest, info)
+		}
+	}
+
+	return &UnsupportedFileTypeError{
+		mode: info.Mode(),
+		path: src,
+	}
+}
+
 // fcopy is for just a file,
 // with considering existence of parent directory
 // and file permission.
-func fcopy(src, dest string, info os.FileInfo) error {
+func fcopy(ctx context.Context, src, dest string, info os.FileInfo, opts Options) error {
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 
 	if err := os.MkdirAll(filepath.Dir(dest), os.ModePerm); err != nil {
 		return err
 	}
 
+	if opts.CopyMode == Hardlink {
+		// Remove any stale dest first: os.Link refuses to replace an existing
+		// path, which is the ordinary case when re-copying into a destination
+		// that already has the file.
+		if err := os.Remove(dest); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		err := os.Link(src, dest)
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, syscall.EXDEV) {
+			return err
+		}
+		// src and dest are on different devices: fall through to a content copy.
+	}
+
 	f, err := os.Create(dest)
 	if err != nil {
 		return err
 	}
 	defer f.Close()
 
-	if err = os.Chmod(f.Name(), info.Mode()); err != nil {
+	chmod := opts.PermissionControl
+	if chmod == nil {
+		chmod = func(src, dest string, info os.FileInfo) error {
+			return os.Chmod(dest, info.Mode()|opts.AddPermission)
+		}
+	}
+	if err = chmod(src, dest, info); err != nil {
 		return err
 	}
 
@@ -166,14 +297,54 @@ func fcopy(src, dest string, info os.FileInfo) error {
 	}
 	defer s.Close()
 
-	_, err = io.Copy(f, s)
-	return err
+	if opts.CopyMode == Reflink || opts.CopyMode == Auto {
+		rerr := reflink(f, s)
+		if rerr == nil {
+			if opts.Sync {
+				return f.Sync()
+			}
+			return nil
+		}
+		if !isReflinkFallbackError(rerr) {
+			return rerr
+		}
+		if opts.CopyMode == Reflink {
+			return &ReflinkUnsupportedError{path: src, err: rerr}
+		}
+		// Auto: fall through to the content copy below.
+	}
+
+	if _, err := io.Copy(f, &ctxReader{ctx: ctx, r: s}); err != nil {
+		return err
+	}
+	if opts.Sync {
+		return f.Sync()
+	}
+	return nil
+}
+
+// ctxReader wraps an io.Reader and aborts with ctx.Err() before reading the
+// next chunk, so a large file's content copy can be cancelled mid-transfer.
+type ctxReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r *ctxReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
 }
 
 // dcopy is for a directory,
 // with scanning contents inside the directory
 // and pass everything to "copy" recursively.
-func dcopy(srcdir, destdir string, info os.FileInfo) error {
+func dcopy(ctx context.Context, srcdir, destdir string, info os.FileInfo, opts Options) error {
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 
 	originalMode := info.Mode()
 
@@ -181,7 +352,9 @@ func dcopy(srcdir, destdir string, info os.FileInfo) error {
 	if err := os.MkdirAll(destdir, tmpPermissionForDirectory); err != nil {
 		return err
 	}
-	// Recover dir mode with original one.
+	// Recover dir mode with original one. This runs after dcopyConcurrent (if
+	// used) has drained every worker for this directory, since it returns
+	// only once all of them are done.
 	defer os.Chmod(destdir, originalMode)
 
 	contents, err := ioutil.ReadDir(srcdir)
@@ -189,28 +362,110 @@ func dcopy(srcdir, destdir string, info os.FileInfo) error {
 		return err
 	}
 
-	for _, content := range contents {
-		cs, cd := filepath.Join(srcdir, content.Name()), filepath.Join(destdir, content.Name())
-		if err := copy(cs, cd, content); err != nil {
-
-			// If we encountered an unsupported file type, exit only if we don't ignore them
-			if _, ok := err.(*UnsupportedFileTypeError); ok {
-				if !IgnoreUnsupportedFileTypes {
+	if opts.NumOfWorkers > 1 {
+		if err := dcopyConcurrent(ctx, srcdir, destdir, contents, opts); err != nil {
+			return err
+		}
+	} else {
+		for _, content := range contents {
+			cs, cd := filepath.Join(srcdir, content.Name()), filepath.Join(destdir, content.Name())
+			if err := copy(ctx, cs, cd, content, opts); err != nil {
+
+				// If we encountered an unsupported file type, exit only if we don't ignore them
+				if _, ok := err.(*UnsupportedFileTypeError); ok {
+					if !IgnoreUnsupportedFileTypes {
+						return err
+					}
+				} else {
+					// If any error, exit immediately
 					return err
 				}
-			} else {
-				// If any error, exit immediately
-				return err
 			}
 		}
 	}
 
+	if opts.Prune {
+		return pruneStaleEntries(destdir, contents)
+	}
+
+	return nil
+}
+
+// pruneStaleEntries removes entries from destdir that have no counterpart
+// among srcContents, giving Options.Prune its rsync "--delete" semantics.
+func pruneStaleEntries(destdir string, srcContents []os.FileInfo) error {
+	wanted := make(map[string]struct{}, len(srcContents))
+	for _, content := range srcContents {
+		wanted[content.Name()] = struct{}{}
+	}
+
+	destContents, err := ioutil.ReadDir(destdir)
+	if err != nil {
+		return err
+	}
+
+	for _, content := range destContents {
+		if _, ok := wanted[content.Name()]; ok {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(destdir, content.Name())); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// dcopyConcurrent copies a directory's immediate contents across a bounded
+// pool of opts.NumOfWorkers goroutines. Each entry still recurses into copy
+// (and, for subdirectories, dcopy) sequentially within its own worker; only
+// siblings run in parallel. It blocks until every entry has been copied, so
+// the caller's PreserveTime/PreserveOwner post-processing for destdir never
+// races a still-running worker.
+func dcopyConcurrent(ctx context.Context, srcdir, destdir string, contents []os.FileInfo, opts Options) error {
+	sem := make(chan struct{}, opts.NumOfWorkers)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var errs []error
+
+	for _, content := range contents {
+		content := content
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			cs, cd := filepath.Join(srcdir, content.Name()), filepath.Join(destdir, content.Name())
+			err := copy(ctx, cs, cd, content, opts)
+			if err == nil {
+				return
+			}
+
+			// If we encountered an unsupported file type, record it only if we don't ignore them
+			if _, ok := err.(*UnsupportedFileTypeError); ok && IgnoreUnsupportedFileTypes {
+				return
+			}
+
+			mu.Lock()
+			errs = append(errs, err)
+			mu.Unlock()
+		}()
+	}
+
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return &MultiError{errors: errs}
+	}
 	return nil
 }
 
 // lcopy is for a symlink,
 // with just creating a new symlink by replicating src symlink.
-func lcopy(src, dest string, info os.FileInfo) error {
+func lcopy(src, dest string, info os.FileInfo, opts Options) error {
 	src, err := os.Readlink(src)
 	if err != nil {
 		return err
@@ -236,3 +491,44 @@ type FileCopyTasksError struct {
 func (e *FileCopyTasksError) Error() string {
 	return fmt.Sprintf("some tasks after the copy of file %s could not be achieved", e.path)
 }
+
+// ConflictError is returned when Options.OnConflict returns ConflictFail for
+// a dest that already exists with a type different from src.
+type ConflictError struct {
+	src  string
+	dest string
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("copy: refusing to replace %s with %s: conflicting file types", e.dest, e.src)
+}
+
+// MultiError aggregates the per-file errors produced by dcopyConcurrent when
+// copying a directory's contents across multiple workers.
+type MultiError struct {
+	errors []error
+}
+
+func (e *MultiError) Error() string {
+	msgs := make([]string, len(e.errors))
+	for i, err := range e.errors {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("copy: %d of the concurrent copies failed: %s", len(e.errors), strings.Join(msgs, "; "))
+}
+
+// ReflinkUnsupportedError is returned when Options.CopyMode is Reflink and
+// the underlying filesystem or platform doesn't support copy-on-write
+// clones. Callers that want a silent fallback instead should use Auto.
+type ReflinkUnsupportedError struct {
+	path string
+	err  error
+}
+
+func (e *ReflinkUnsupportedError) Error() string {
+	return fmt.Sprintf("reflink is not supported for %s: %s", e.path, e.err)
+}
+
+func (e *ReflinkUnsupportedError) Unwrap() error {
+	return e.err
+}
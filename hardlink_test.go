@@ -0,0 +1,36 @@
+package copy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCopyHardlinkOverwritesExistingDest(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src")
+	dest := filepath.Join(dir, "dest")
+
+	if err := os.WriteFile(src, []byte("first"), 0644); err != nil {
+		t.Fatalf("WriteFile(src): %v", err)
+	}
+
+	opts := Options{CopyMode: Hardlink}
+	if err := Copy(src, dest, opts); err != nil {
+		t.Fatalf("first Copy: %v", err)
+	}
+
+	// Re-running the copy into an already-populated dest (e.g. a re-run of
+	// the same job) must not fail with "file exists".
+	if err := Copy(src, dest, opts); err != nil {
+		t.Fatalf("second Copy (dest already exists): %v", err)
+	}
+
+	got, err := os.ReadFile(dest)
+	if err != nil {
+		t.Fatalf("ReadFile(dest): %v", err)
+	}
+	if string(got) != "first" {
+		t.Errorf("dest content = %q, want %q", got, "first")
+	}
+}
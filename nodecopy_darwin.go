@@ -0,0 +1,22 @@
+//go:build darwin
+
+package copy
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// nodecopy is the Darwin variant of the Linux implementation in
+// nodecopy_linux.go: stat.Mode (uint16) and stat.Rdev (int32) are narrower
+// here and need widening casts.
+func nodecopy(src, dest string, info os.FileInfo) error {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return fmt.Errorf("copy: could not read device number for %s", src)
+	}
+	return unix.Mknod(dest, uint32(stat.Mode), int(stat.Rdev))
+}
@@ -0,0 +1,21 @@
+//go:build freebsd
+
+package copy
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// nodecopy is the FreeBSD variant of the Linux implementation in
+// nodecopy_linux.go: unix.Mknod's dev argument is a uint64 here, not an int.
+func nodecopy(src, dest string, info os.FileInfo) error {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return fmt.Errorf("copy: could not read device number for %s", src)
+	}
+	return unix.Mknod(dest, uint32(stat.Mode), uint64(stat.Rdev))
+}
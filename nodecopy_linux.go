@@ -0,0 +1,23 @@
+//go:build linux
+
+package copy
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// nodecopy recreates a character or block device node at dest, matching
+// src's mode bits and device number. It uses stat.Mode rather than
+// info.Mode(), since only the former encodes the raw S_IFCHR/S_IFBLK bits
+// the kernel expects.
+func nodecopy(src, dest string, info os.FileInfo) error {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return fmt.Errorf("copy: could not read device number for %s", src)
+	}
+	return unix.Mknod(dest, stat.Mode, int(stat.Rdev))
+}
@@ -0,0 +1,22 @@
+//go:build netbsd || openbsd
+
+package copy
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// nodecopy is the NetBSD/OpenBSD variant of the Linux implementation in
+// nodecopy_linux.go: unix.Mknod's dev argument is an int here, same width as
+// linux/darwin, and stat.Mode is already a uint32, so its cast is a no-op.
+func nodecopy(src, dest string, info os.FileInfo) error {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return fmt.Errorf("copy: could not read device number for %s", src)
+	}
+	return unix.Mknod(dest, uint32(stat.Mode), int(stat.Rdev))
+}
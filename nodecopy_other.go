@@ -0,0 +1,10 @@
+//go:build windows || js
+
+package copy
+
+import "os"
+
+// nodecopy has no equivalent on this platform.
+func nodecopy(src, dest string, info os.FileInfo) error {
+	return &UnsupportedFileTypeError{mode: info.Mode(), path: src}
+}
@@ -0,0 +1,123 @@
+package copy
+
+import "os"
+
+// SymlinkAction tells copy how to handle a symlink found in the source tree.
+type SymlinkAction int
+
+const (
+	// Shallow creates a new symlink in dest pointing at the same target as src.
+	// This is the default behavior.
+	Shallow SymlinkAction = iota
+	// Deep resolves the symlink and copies whatever it points to, as if src
+	// were the resolved path.
+	Deep
+	// Skip leaves dest untouched and does not copy the symlink at all.
+	Skip
+)
+
+// Options customizes the behavior of a single Copy call. Unlike the
+// package-level globals, an Options value is never mutated by Copy and is
+// therefore safe to reuse across concurrent calls.
+type Options struct {
+	// PreservePermissions preserves the permission of the source files and folder in the destination.
+	PreservePermissions bool
+	// PreserveOwner preserves the owner of the source files and folder in the destination.
+	PreserveOwner bool
+	// PreserveTime preserves the access and modification times of the source files and folder in the destination.
+	PreserveTime bool
+
+	// OnSymlink decides how a symlink found in src should be handled. If nil,
+	// symlinks are replicated as new symlinks (Shallow).
+	OnSymlink func(src string) SymlinkAction
+
+	// Skip, if set, is consulted for every src/dest pair before it is copied.
+	// Returning true skips the entry (and, for a directory, everything under it).
+	Skip func(info os.FileInfo, src, dest string) (bool, error)
+
+	// PermissionControl, if set, replaces the default logic fcopy uses to set
+	// the permission of a freshly created destination file.
+	PermissionControl func(src, dest string, info os.FileInfo) error
+
+	// AddPermission is OR'd onto src's mode when fcopy sets the permission of a
+	// newly created destination file. It is ignored when PermissionControl is set.
+	AddPermission os.FileMode
+
+	// Sync fsyncs each copied file before it is closed.
+	Sync bool
+
+	// NumOfWorkers controls how many files dcopy copies concurrently. A value
+	// <= 1 copies siblings sequentially, matching the historical behavior.
+	NumOfWorkers int64
+
+	// CopyMode selects the strategy fcopy uses to place src's content at
+	// dest. It defaults to Content, which is always available.
+	CopyMode CopyMode
+
+	// OnConflict, if set, is consulted when dest already exists with a type
+	// different from src (e.g. dest is a file but src is a directory, or vice
+	// versa). If nil, Overwrite decides: false leaves the conflicting path
+	// for the underlying copy to fail on as before, true always replaces it.
+	OnConflict func(src, dest string, srcInfo, destInfo os.FileInfo) ConflictAction
+
+	// Overwrite is a shortcut for OnConflict that always resolves a type
+	// conflict by replacing dest.
+	Overwrite bool
+
+	// Prune mirrors dest to exactly match src for every directory copied into
+	// dest: once a directory's contents have been copied, any dest entries
+	// absent from src are removed (rsync "--delete" semantics).
+	Prune bool
+}
+
+// CopyMode selects the underlying strategy fcopy uses to duplicate a file's
+// content at the destination.
+type CopyMode int
+
+const (
+	// Content copies file content byte-by-byte via io.Copy. This is the
+	// default and is always available.
+	Content CopyMode = iota
+	// Hardlink creates a hard link to src instead of duplicating its content.
+	// It falls back to Content when src and dest are on different devices.
+	Hardlink
+	// Reflink creates a copy-on-write clone of src using the filesystem's
+	// reflink support (e.g. the FICLONE ioctl on Linux/XFS/Btrfs). If the
+	// filesystem or platform doesn't support it, a *ReflinkUnsupportedError
+	// is returned.
+	Reflink
+	// Auto behaves like Reflink, but silently falls back to Content instead
+	// of returning an error when reflink isn't supported.
+	Auto
+)
+
+// ConflictAction tells copy how to resolve a dest that already exists with a
+// type different from src, as decided by Options.OnConflict.
+type ConflictAction int
+
+const (
+	// ConflictReplace removes the existing dest (os.RemoveAll) before copying
+	// src over it.
+	ConflictReplace ConflictAction = iota
+	// ConflictSkip leaves dest untouched and does not copy src at all.
+	ConflictSkip
+	// ConflictMerge keeps dest as-is (directories only) and copies src's
+	// contents into it, same as when no conflict was detected.
+	ConflictMerge
+	// ConflictFail aborts the copy with a *ConflictError.
+	ConflictFail
+)
+
+// optionsOrDefault returns the Options given by the caller, or, if none were
+// given, an Options built from the legacy package-level globals so that
+// Copy(src, dest) keeps behaving exactly as it did before Options existed.
+func optionsOrDefault(opts []Options) Options {
+	if len(opts) > 0 {
+		return opts[0]
+	}
+	return Options{
+		PreservePermissions: PreservePermissions,
+		PreserveOwner:       PreserveOwner,
+		PreserveTime:        PreserveTime,
+	}
+}
@@ -0,0 +1,10 @@
+//go:build windows || js
+
+package copy
+
+import "os"
+
+// pcopy has no equivalent on this platform.
+func pcopy(src, dest string, info os.FileInfo) error {
+	return &UnsupportedFileTypeError{mode: info.Mode(), path: src}
+}
@@ -0,0 +1,13 @@
+//go:build linux || darwin || freebsd || openbsd || netbsd
+
+package copy
+
+import (
+	"os"
+	"syscall"
+)
+
+// pcopy recreates a named pipe (FIFO) at dest with src's permission bits.
+func pcopy(src, dest string, info os.FileInfo) error {
+	return syscall.Mkfifo(dest, uint32(info.Mode().Perm()))
+}
@@ -0,0 +1,32 @@
+//go:build linux || darwin || freebsd || openbsd || netbsd
+
+package copy
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+func TestCopyFIFO(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src-fifo")
+	dest := filepath.Join(dir, "dest-fifo")
+
+	if err := syscall.Mkfifo(src, 0644); err != nil {
+		t.Fatalf("Mkfifo(%s): %v", src, err)
+	}
+
+	if err := Copy(src, dest); err != nil {
+		t.Fatalf("Copy: %v", err)
+	}
+
+	info, err := os.Lstat(dest)
+	if err != nil {
+		t.Fatalf("Lstat(%s): %v", dest, err)
+	}
+	if info.Mode()&os.ModeNamedPipe == 0 {
+		t.Fatalf("dest mode = %s, want a named pipe", info.Mode())
+	}
+}
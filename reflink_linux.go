@@ -0,0 +1,31 @@
+//go:build linux
+
+package copy
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// ficlone is the FICLONE ioctl request number: _IOW(0x94, 9, int).
+const ficlone = 0x40049409
+
+// reflink asks the filesystem to make dest a copy-on-write clone of src's
+// current content, via the Linux FICLONE ioctl (supported on e.g. Btrfs,
+// XFS with reflink=1, and overlayfs over either of those).
+func reflink(dest, src *os.File) error {
+	return unix.IoctlSetInt(int(dest.Fd()), ficlone, int(src.Fd()))
+}
+
+// isReflinkFallbackError reports whether err is one of the well-known errno
+// values FICLONE returns when the source/destination pair simply doesn't
+// support reflink, as opposed to a real failure that should be surfaced.
+func isReflinkFallbackError(err error) bool {
+	switch err {
+	case unix.EOPNOTSUPP, unix.EXDEV, unix.EINVAL, unix.ENOTTY:
+		return true
+	default:
+		return false
+	}
+}
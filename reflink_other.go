@@ -0,0 +1,21 @@
+//go:build !linux
+
+package copy
+
+import (
+	"errors"
+	"os"
+)
+
+// errReflinkUnsupported is what reflink always reports on this platform.
+var errReflinkUnsupported = errors.New("copy: reflink is not supported on this platform")
+
+// reflink has no equivalent outside Linux, so it always reports that the
+// caller should fall back to a normal content copy.
+func reflink(dest, src *os.File) error {
+	return errReflinkUnsupported
+}
+
+func isReflinkFallbackError(err error) bool {
+	return err == errReflinkUnsupported
+}
@@ -0,0 +1,11 @@
+//go:build windows || js
+
+package copy
+
+import "os"
+
+// owner has no equivalent on this platform and always reports ok=false, so
+// Options.PreserveOwner makes copy return a *FileCopyTasksError here.
+func owner(info os.FileInfo) (uid, gid int, ok bool) {
+	return 0, 0, false
+}
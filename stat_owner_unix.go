@@ -0,0 +1,18 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd
+
+package copy
+
+import (
+	"os"
+	"syscall"
+)
+
+// owner extracts src's uid/gid from its syscall.Stat_t, for use by
+// Options.PreserveOwner.
+func owner(info os.FileInfo) (uid, gid int, ok bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, 0, false
+	}
+	return int(stat.Uid), int(stat.Gid), true
+}
@@ -0,0 +1,32 @@
+//go:build freebsd || netbsd
+
+package copy
+
+import (
+	"os"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// times is the FreeBSD/NetBSD variant of the Linux implementation in
+// stat_times_linux.go: these name the Stat_t fields Atimespec/Mtimespec/
+// Ctimespec, same as Darwin, rather than Linux's Atim/Mtim/Ctim.
+func times(info os.FileInfo) (atime, mtime, ctime time.Time) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return time.Time{}, info.ModTime(), time.Time{}
+	}
+	return time.Unix(stat.Atimespec.Sec, stat.Atimespec.Nsec),
+		time.Unix(stat.Mtimespec.Sec, stat.Mtimespec.Nsec),
+		time.Unix(stat.Ctimespec.Sec, stat.Ctimespec.Nsec)
+}
+
+// lutimes restores atime/mtime on path without following a trailing symlink.
+func lutimes(path string, atime, mtime time.Time) error {
+	return unix.UtimesNanoAt(unix.AT_FDCWD, path, []unix.Timespec{
+		unix.NsecToTimespec(atime.UnixNano()),
+		unix.NsecToTimespec(mtime.UnixNano()),
+	}, unix.AT_SYMLINK_NOFOLLOW)
+}
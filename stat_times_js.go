@@ -0,0 +1,20 @@
+//go:build js
+
+package copy
+
+import (
+	"os"
+	"time"
+)
+
+// times has no Stat_t equivalent under js/wasm; only mtime is available,
+// via os.FileInfo.ModTime.
+func times(info os.FileInfo) (atime, mtime, ctime time.Time) {
+	return time.Time{}, info.ModTime(), time.Time{}
+}
+
+// lutimes restores mtime on path via os.Chtimes; js/wasm has no
+// symlink-safe utimes equivalent.
+func lutimes(path string, atime, mtime time.Time) error {
+	return os.Chtimes(path, atime, mtime)
+}
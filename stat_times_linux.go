@@ -0,0 +1,31 @@
+//go:build linux
+
+package copy
+
+import (
+	"os"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// times extracts src's access, modification, and change times from its
+// syscall.Stat_t, for use by Options.PreserveTime.
+func times(info os.FileInfo) (atime, mtime, ctime time.Time) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return time.Time{}, info.ModTime(), time.Time{}
+	}
+	return time.Unix(stat.Atim.Sec, stat.Atim.Nsec),
+		time.Unix(stat.Mtim.Sec, stat.Mtim.Nsec),
+		time.Unix(stat.Ctim.Sec, stat.Ctim.Nsec)
+}
+
+// lutimes restores atime/mtime on path without following a trailing symlink.
+func lutimes(path string, atime, mtime time.Time) error {
+	return unix.Lutimes(path, []unix.Timeval{
+		unix.NsecToTimeval(atime.UnixNano()),
+		unix.NsecToTimeval(mtime.UnixNano()),
+	})
+}
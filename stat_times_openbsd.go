@@ -0,0 +1,32 @@
+//go:build openbsd
+
+package copy
+
+import (
+	"os"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// times is the OpenBSD variant of the Linux implementation in
+// stat_times_linux.go: OpenBSD's Stat_t names these fields Atim/Mtim/Ctim,
+// same as Linux, unlike FreeBSD/NetBSD's Atimespec/Mtimespec/Ctimespec.
+func times(info os.FileInfo) (atime, mtime, ctime time.Time) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return time.Time{}, info.ModTime(), time.Time{}
+	}
+	return time.Unix(stat.Atim.Sec, stat.Atim.Nsec),
+		time.Unix(stat.Mtim.Sec, stat.Mtim.Nsec),
+		time.Unix(stat.Ctim.Sec, stat.Ctim.Nsec)
+}
+
+// lutimes restores atime/mtime on path without following a trailing symlink.
+func lutimes(path string, atime, mtime time.Time) error {
+	return unix.UtimesNanoAt(unix.AT_FDCWD, path, []unix.Timespec{
+		unix.NsecToTimespec(atime.UnixNano()),
+		unix.NsecToTimespec(mtime.UnixNano()),
+	}, unix.AT_SYMLINK_NOFOLLOW)
+}
@@ -0,0 +1,54 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd
+
+package copy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestPreserveTimeRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src")
+	dest := filepath.Join(dir, "dest")
+
+	if err := os.WriteFile(src, []byte("hello"), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	past := time.Now().Add(-48 * time.Hour)
+	if err := os.Chtimes(src, past, past); err != nil {
+		t.Fatalf("Chtimes: %v", err)
+	}
+
+	// Snapshot src's times before Copy opens (and, on relatime mounts, bumps
+	// the atime of) the file.
+	srcInfo, err := os.Lstat(src)
+	if err != nil {
+		t.Fatalf("Lstat(src): %v", err)
+	}
+	srcAtime, srcMtime, _ := times(srcInfo)
+
+	if err := Copy(src, dest, Options{PreserveTime: true}); err != nil {
+		t.Fatalf("Copy: %v", err)
+	}
+
+	destInfo, err := os.Lstat(dest)
+	if err != nil {
+		t.Fatalf("Lstat(dest): %v", err)
+	}
+	destAtime, destMtime, _ := times(destInfo)
+
+	// lutimes round-trips through unix.Timeval on some platforms, which only
+	// has microsecond resolution, so compare with a tolerance rather than
+	// requiring exact equality.
+	const tolerance = time.Millisecond
+	if diff := destMtime.Sub(srcMtime); diff < -tolerance || diff > tolerance {
+		t.Errorf("dest mtime = %s, want %s (diff %s exceeds %s)", destMtime, srcMtime, diff, tolerance)
+	}
+	if diff := destAtime.Sub(srcAtime); diff < -tolerance || diff > tolerance {
+		t.Errorf("dest atime = %s, want %s (diff %s exceeds %s)", destAtime, srcAtime, diff, tolerance)
+	}
+}
@@ -0,0 +1,28 @@
+//go:build windows
+
+package copy
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+// times extracts src's access, modification, and creation times from its
+// syscall.Win32FileAttributeData, for use by Options.PreserveTime.
+func times(info os.FileInfo) (atime, mtime, ctime time.Time) {
+	stat, ok := info.Sys().(*syscall.Win32FileAttributeData)
+	if !ok {
+		return time.Time{}, info.ModTime(), time.Time{}
+	}
+	return time.Unix(0, stat.LastAccessTime.Nanoseconds()),
+		time.Unix(0, stat.LastWriteTime.Nanoseconds()),
+		time.Unix(0, stat.CreationTime.Nanoseconds())
+}
+
+// lutimes restores atime/mtime on path. Windows has no symlink-safe
+// equivalent of utimensat(AT_SYMLINK_NOFOLLOW), so this follows a trailing
+// symlink like os.Chtimes.
+func lutimes(path string, atime, mtime time.Time) error {
+	return os.Chtimes(path, atime, mtime)
+}